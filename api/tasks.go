@@ -0,0 +1,27 @@
+package api
+
+// TaskHookCommand is the jobspec equivalent of structs.TaskHookCommand: an
+// operator-declared external command hook, configured in a task's `hooks`
+// stanza and bound to one lifecycle phase via Task.Hooks.
+type TaskHookCommand struct {
+	Command    string
+	Args       []string
+	WorkingDir string `mapstructure:"working_dir"`
+	Env        []string
+	Timeout    string
+	OnError    string `mapstructure:"on_error"`
+}
+
+// Task is the jobspec equivalent of structs.Task.
+type Task struct {
+	Name   string
+	Driver string
+	Config map[string]interface{}
+	Env    map[string]string
+	Meta   map[string]string
+
+	// Hooks maps a lifecycle phase (prestart, poststart, stop, kill,
+	// update) to the command hook declared for it in the task's `hooks`
+	// stanza.
+	Hooks map[string]*TaskHookCommand
+}
@@ -0,0 +1,124 @@
+package interfaces
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"text/template"
+
+	"github.com/hashicorp/nomad/client/allocrunnerv2/taskrunner/taskenv"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// TaskHook is the base interface all task lifecycle hooks must implement.
+type TaskHook interface {
+	Name() string
+}
+
+type TaskPrestartHook interface {
+	TaskHook
+	Prestart(ctx context.Context, req *TaskPrestartRequest, resp *TaskPrestartResponse) error
+}
+
+type TaskPoststartHook interface {
+	TaskHook
+	Poststart(ctx context.Context, req *TaskPoststartRequest, resp *TaskPoststartResponse) error
+}
+
+type TaskStopHook interface {
+	TaskHook
+	Stop(ctx context.Context, req *TaskStopRequest, resp *TaskStopResponse) error
+}
+
+type TaskUpdateHook interface {
+	TaskHook
+	Update(ctx context.Context, req *TaskUpdateRequest, resp *TaskUpdateResponse) error
+}
+
+type TaskKillHook interface {
+	TaskHook
+	Kill(ctx context.Context, req *TaskKillRequest, resp *TaskKillResponse) error
+}
+
+// HookVars is a uniform interpolation context built once per lifecycle
+// invocation (eg once per call to prestart, poststart, stop, kill, or
+// update) and passed to every hook run during that invocation. Hooks that
+// need to render strings - the user command hook chief among them - render
+// against this struct via text/template instead of calling
+// envBuilder.Build() directly.
+//
+// HookVars lives in this package, rather than in taskrunner where it's
+// built, so that the Task*Request structs below can carry one without
+// taskrunner and interfaces importing each other.
+type HookVars struct {
+	Alloc    *structs.Allocation
+	JobID    string
+	TaskName string
+	NodeID   string
+	TaskDir  string
+	Env      map[string]string
+
+	// Outputs holds the stdout of each command hook that has already run
+	// during this invocation, keyed by lifecycle phase (eg "prestart"), so a
+	// later hook can reference an earlier one's result.
+	Outputs map[string]string
+
+	// LastEvent is the most recent task event recorded before this
+	// invocation began.
+	LastEvent *structs.TaskEvent
+}
+
+// Render interpolates s against the vars using text/template, eg
+// "{{.Env.NOMAD_ALLOC_ID}}" or "{{.Outputs.prestart}}".
+func (v *HookVars) Render(s string) (string, error) {
+	tmpl, err := template.New("hookvars").Parse(s)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse hook template %q: %v", s, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, v); err != nil {
+		return "", fmt.Errorf("failed to render hook template %q: %v", s, err)
+	}
+
+	return buf.String(), nil
+}
+
+type TaskPrestartRequest struct {
+	Task       *structs.Task
+	TaskDir    string
+	TaskEnv    *taskenv.TaskEnv
+	VaultToken string
+	Vars       *HookVars
+}
+
+type TaskPrestartResponse struct {
+	Env      map[string]string
+	HookData map[string]string
+	Done     bool
+}
+
+type TaskPoststartRequest struct {
+	Vars *HookVars
+}
+
+type TaskPoststartResponse struct{}
+
+type TaskStopRequest struct {
+	Vars *HookVars
+}
+
+type TaskStopResponse struct{}
+
+type TaskUpdateRequest struct {
+	VaultToken string
+	Vars       *HookVars
+}
+
+type TaskUpdateResponse struct{}
+
+type TaskKillRequest struct {
+	Vars *HookVars
+}
+
+type TaskKillResponse struct{}
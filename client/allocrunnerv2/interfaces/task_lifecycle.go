@@ -0,0 +1,27 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// EventEmitter is implemented by TaskRunner and lets hooks surface task
+// events without depending on TaskRunner directly.
+type EventEmitter interface {
+	EmitEvent(event *structs.TaskEvent)
+}
+
+// TaskLifecycle is implemented by TaskRunner and lets hooks request
+// restarts, signals, or kills without depending on TaskRunner directly.
+type TaskLifecycle interface {
+	Restart(ctx context.Context, event *structs.TaskEvent, failure bool) error
+	Signal(event *structs.TaskEvent, s string) error
+	Kill(ctx context.Context, event *structs.TaskEvent) error
+}
+
+// TaskUpdater is implemented by TaskRunner and lets hooks propagate task
+// state changes without depending on TaskRunner directly.
+type TaskUpdater interface {
+	UpdateState(state string, event *structs.TaskEvent)
+}
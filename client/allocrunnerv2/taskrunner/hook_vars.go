@@ -0,0 +1,27 @@
+package taskrunner
+
+import (
+	"github.com/hashicorp/nomad/client/allocrunnerv2/interfaces"
+)
+
+// newHookVars builds the interfaces.HookVars interpolation context for a
+// single lifecycle invocation of tr's hooks.
+func newHookVars(tr *TaskRunner) *interfaces.HookVars {
+	alloc := tr.Alloc()
+
+	vars := &interfaces.HookVars{
+		Alloc:    alloc,
+		JobID:    alloc.JobID,
+		TaskName: tr.taskName,
+		NodeID:   alloc.NodeID,
+		TaskDir:  tr.taskDir.Dir,
+		Env:      tr.envBuilder.Build().Map(),
+		Outputs:  make(map[string]string),
+	}
+
+	if state := alloc.TaskStates[tr.taskName]; state != nil && len(state.Events) > 0 {
+		vars.LastEvent = state.Events[len(state.Events)-1]
+	}
+
+	return vars
+}
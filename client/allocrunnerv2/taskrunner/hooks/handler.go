@@ -0,0 +1,71 @@
+// Package hooks lets code outside of taskrunner contribute additional task
+// lifecycle hooks without patching TaskRunner.initHooks directly. Downstream
+// forks and enterprise plugins register a Handler (eg for secrets brokers,
+// custom attestation, or audit shippers) and TaskRunner builds a hook from
+// it for every task the Handler wants to handle.
+package hooks
+
+import (
+	"sync"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/allocdir"
+	"github.com/hashicorp/nomad/client/allocrunnerv2/interfaces"
+	"github.com/hashicorp/nomad/client/allocrunnerv2/taskrunner/taskenv"
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/client/vaultclient"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// HookContext carries the same dependencies TaskRunner uses to build its
+// own built-in hooks, so a registered Handler has the same surface to work
+// with that newVaultHook, newTemplateHook, etc. get today.
+type HookContext struct {
+	ClientConfig *config.Config
+	Alloc        *structs.Allocation
+	Task         *structs.Task
+	TaskDir      *allocdir.TaskDir
+	EnvBuilder   *taskenv.Builder
+	VaultClient  vaultclient.VaultClient
+	Logger       hclog.Logger
+
+	Lifecycle interfaces.TaskLifecycle
+	Events    interfaces.EventEmitter
+	Updater   interfaces.TaskUpdater
+}
+
+// Handler builds additional TaskHooks for tasks it recognizes.
+type Handler interface {
+	// ShouldHandle reports whether this handler wants to attach a hook to
+	// the given task.
+	ShouldHandle(task *structs.Task) bool
+
+	// Build constructs the hook for the task described by ctx. Only called
+	// when ShouldHandle returned true for ctx.Task.
+	Build(ctx HookContext) interfaces.TaskHook
+}
+
+var (
+	mu         sync.Mutex
+	registered []Handler
+)
+
+// Register adds a Handler to the set consulted by every TaskRunner when it
+// builds its hook chain. It is meant to be called from an init() function
+// in a package that wants to extend Nomad's task lifecycle; registration
+// has no effect on tasks whose hooks have already been built.
+func Register(h Handler) {
+	mu.Lock()
+	defer mu.Unlock()
+	registered = append(registered, h)
+}
+
+// Handlers returns a snapshot of the currently registered handlers.
+func Handlers() []Handler {
+	mu.Lock()
+	defer mu.Unlock()
+
+	out := make([]Handler, len(registered))
+	copy(out, registered)
+	return out
+}
@@ -0,0 +1,36 @@
+package hooks
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/client/allocrunnerv2/interfaces"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+type fakeHandler struct {
+	name string
+}
+
+func (h *fakeHandler) ShouldHandle(task *structs.Task) bool { return true }
+
+func (h *fakeHandler) Build(ctx HookContext) interfaces.TaskHook {
+	return nil
+}
+
+func TestRegisterHandlers(t *testing.T) {
+	before := len(Handlers())
+
+	Register(&fakeHandler{name: "test-handler"})
+
+	after := Handlers()
+	if len(after) != before+1 {
+		t.Fatalf("expected %d handlers, got %d", before+1, len(after))
+	}
+
+	// Handlers() should return a snapshot: mutating the returned slice must
+	// not affect future callers.
+	after[0] = nil
+	if Handlers()[0] == nil {
+		t.Fatalf("expected Handlers() to return a defensive copy")
+	}
+}
@@ -5,11 +5,54 @@ import (
 	"fmt"
 	"time"
 
+	multierror "github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/nomad/client/allocrunnerv2/interfaces"
+	"github.com/hashicorp/nomad/client/allocrunnerv2/taskrunner/hooks"
 	"github.com/hashicorp/nomad/client/allocrunnerv2/taskrunner/state"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
 
+// defaultHookTimeout bounds how long a single stop/kill hook may
+// run when the task sets neither ShutdownDelay nor KillTimeout, so a
+// misbehaving hook can't block allocation teardown indefinitely.
+const defaultHookTimeout = 5 * time.Second
+
+// hookTimeout returns how long a single stop/kill hook is allowed to run
+// for task.
+func hookTimeout(task *structs.Task) time.Duration {
+	if task.ShutdownDelay > 0 {
+		return task.ShutdownDelay
+	}
+	if task.KillTimeout > 0 {
+		return task.KillTimeout
+	}
+	return defaultHookTimeout
+}
+
+// continueOnHookError reports whether the lifecycle loop should keep
+// running the remaining hooks after hook has failed. Hooks opt out of the
+// default (continue) by implementing an optional ContinueOnError() bool
+// method and returning false.
+func continueOnHookError(hook interfaces.TaskHook) bool {
+	coe, ok := hook.(interface{ ContinueOnError() bool })
+	if !ok {
+		return true
+	}
+	return coe.ContinueOnError()
+}
+
+// runHookRecover runs fn, converting a panic into an error so that one
+// misbehaving hook can't prevent the remaining hooks from running.
+func (tr *TaskRunner) runHookRecover(name string, fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			tr.logger.Error("hook panicked", "name", name, "error", r)
+			err = fmt.Errorf("hook %q panicked: %v", name, r)
+		}
+	}()
+	return fn()
+}
+
 // initHooks intializes the tasks hooks.
 func (tr *TaskRunner) initHooks() {
 	hookLogger := tr.logger.Named("task_hook")
@@ -49,6 +92,34 @@ func (tr *TaskRunner) initHooks() {
 			envBuilder:   tr.envBuilder,
 		}))
 	}
+
+	// Add an operator-declared command hook for each lifecycle phase named
+	// in the task's `hooks` stanza.
+	for phase, cmd := range task.Hooks {
+		tr.runnerHooks = append(tr.runnerHooks, newUserCommandHook(phase, cmd, tr, hookLogger))
+	}
+
+	// Give registered third-party handlers a chance to attach additional
+	// hooks to this task, using the same dependencies the built-in hooks
+	// above were constructed with.
+	handlerCtx := hooks.HookContext{
+		ClientConfig: tr.clientConfig,
+		Alloc:        tr.Alloc(),
+		Task:         task,
+		TaskDir:      tr.taskDir,
+		EnvBuilder:   tr.envBuilder,
+		VaultClient:  tr.vaultClient,
+		Logger:       hookLogger,
+		Lifecycle:    tr,
+		Events:       tr,
+		Updater:      tr,
+	}
+	for _, handler := range hooks.Handlers() {
+		if !handler.ShouldHandle(task) {
+			continue
+		}
+		tr.runnerHooks = append(tr.runnerHooks, handler.Build(handlerCtx))
+	}
 }
 
 // prestart is used to run the runners prestart hooks.
@@ -72,6 +143,8 @@ func (tr *TaskRunner) prestart() error {
 		}()
 	}
 
+	vars := newHookVars(tr)
+
 	for _, hook := range tr.runnerHooks {
 		pre, ok := hook.(interfaces.TaskPrestartHook)
 		if !ok {
@@ -85,6 +158,7 @@ func (tr *TaskRunner) prestart() error {
 			Task:    tr.Task(),
 			TaskDir: tr.taskDir.Dir,
 			TaskEnv: tr.envBuilder.Build(),
+			Vars:    vars,
 		}
 
 		tr.localStateLock.RLock()
@@ -159,6 +233,9 @@ func (tr *TaskRunner) poststart() error {
 		}()
 	}
 
+	vars := newHookVars(tr)
+
+	var mErr multierror.Error
 	for _, hook := range tr.runnerHooks {
 		post, ok := hook.(interfaces.TaskPoststartHook)
 		if !ok {
@@ -172,11 +249,16 @@ func (tr *TaskRunner) poststart() error {
 			tr.logger.Trace("running poststart hook", "name", name, "start", start)
 		}
 
-		req := interfaces.TaskPoststartRequest{}
+		req := interfaces.TaskPoststartRequest{Vars: vars}
 		var resp interfaces.TaskPoststartResponse
-		// XXX We shouldn't exit on the first one
-		if err := post.Poststart(tr.ctx, &req, &resp); err != nil {
-			return fmt.Errorf("poststart hook %q failed: %v", name, err)
+		err := tr.runHookRecover(name, func() error {
+			return post.Poststart(tr.ctx, &req, &resp)
+		})
+		if err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("poststart hook %q failed: %v", name, err))
+			if !continueOnHookError(hook) {
+				break
+			}
 		}
 
 		if tr.logger.IsTrace() {
@@ -185,7 +267,7 @@ func (tr *TaskRunner) poststart() error {
 		}
 	}
 
-	return nil
+	return mErr.ErrorOrNil()
 }
 
 // stop is used to run the stop hooks.
@@ -199,6 +281,13 @@ func (tr *TaskRunner) stop() error {
 		}()
 	}
 
+	vars := newHookVars(tr)
+
+	// timeout bounds each stop hook individually, so a misbehaving hook
+	// can't block teardown and the hooks after it still get a full budget.
+	timeout := hookTimeout(tr.Task())
+
+	var mErr multierror.Error
 	for _, hook := range tr.runnerHooks {
 		post, ok := hook.(interfaces.TaskStopHook)
 		if !ok {
@@ -212,11 +301,18 @@ func (tr *TaskRunner) stop() error {
 			tr.logger.Trace("running stop hook", "name", name, "start", start)
 		}
 
-		req := interfaces.TaskStopRequest{}
+		req := interfaces.TaskStopRequest{Vars: vars}
 		var resp interfaces.TaskStopResponse
-		// XXX We shouldn't exit on the first one
-		if err := post.Stop(tr.ctx, &req, &resp); err != nil {
-			return fmt.Errorf("stop hook %q failed: %v", name, err)
+		ctx, cancel := context.WithTimeout(tr.ctx, timeout)
+		err := tr.runHookRecover(name, func() error {
+			return post.Stop(ctx, &req, &resp)
+		})
+		cancel()
+		if err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("stop hook %q failed: %v", name, err))
+			if !continueOnHookError(hook) {
+				break
+			}
 		}
 
 		if tr.logger.IsTrace() {
@@ -225,7 +321,7 @@ func (tr *TaskRunner) stop() error {
 		}
 	}
 
-	return nil
+	return mErr.ErrorOrNil()
 }
 
 // update is used to run the runners update hooks.
@@ -239,6 +335,8 @@ func (tr *TaskRunner) updateHooks() {
 		}()
 	}
 
+	vars := newHookVars(tr)
+
 	for _, hook := range tr.runnerHooks {
 		upd, ok := hook.(interfaces.TaskUpdateHook)
 		if !ok {
@@ -251,6 +349,7 @@ func (tr *TaskRunner) updateHooks() {
 		// Build the request
 		req := interfaces.TaskUpdateRequest{
 			VaultToken: tr.getVaultToken(),
+			Vars:       vars,
 		}
 
 		// Time the update hook
@@ -274,7 +373,7 @@ func (tr *TaskRunner) updateHooks() {
 }
 
 // kill is used to run the runners kill hooks.
-func (tr *TaskRunner) kill() {
+func (tr *TaskRunner) kill() error {
 	if tr.logger.IsTrace() {
 		start := time.Now()
 		tr.logger.Trace("running kill hooks", "start", start)
@@ -284,6 +383,13 @@ func (tr *TaskRunner) kill() {
 		}()
 	}
 
+	vars := newHookVars(tr)
+
+	// timeout bounds each kill hook individually, so a misbehaving hook
+	// can't block teardown and the hooks after it still get a full budget.
+	timeout := hookTimeout(tr.Task())
+
+	var mErr multierror.Error
 	for _, hook := range tr.runnerHooks {
 		upd, ok := hook.(interfaces.TaskKillHook)
 		if !ok {
@@ -300,11 +406,20 @@ func (tr *TaskRunner) kill() {
 			tr.logger.Trace("running kill hook", "name", name, "start", start)
 		}
 
-		// Run the update hook
-		req := interfaces.TaskKillRequest{}
+		// Run the kill hook
+		req := interfaces.TaskKillRequest{Vars: vars}
 		var resp interfaces.TaskKillResponse
-		if err := upd.Kill(context.Background(), &req, &resp); err != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		err := tr.runHookRecover(name, func() error {
+			return upd.Kill(ctx, &req, &resp)
+		})
+		cancel()
+		if err != nil {
 			tr.logger.Error("kill hook failed", "name", name, "error", err)
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("kill hook %q failed: %v", name, err))
+			if !continueOnHookError(hook) {
+				break
+			}
 		}
 
 		if tr.logger.IsTrace() {
@@ -312,6 +427,8 @@ func (tr *TaskRunner) kill() {
 			tr.logger.Trace("finished kill hooks", "name", name, "end", end, "duration", end.Sub(start))
 		}
 	}
+
+	return mErr.ErrorOrNil()
 }
 
 /*
@@ -0,0 +1,67 @@
+package taskrunner
+
+import (
+	"testing"
+	"time"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/allocrunnerv2/interfaces"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+func TestHookTimeout(t *testing.T) {
+	cases := []struct {
+		name string
+		task *structs.Task
+		want time.Duration
+	}{
+		{"defaults when unset", &structs.Task{}, defaultHookTimeout},
+		{"uses shutdown delay", &structs.Task{ShutdownDelay: 10 * time.Second}, 10 * time.Second},
+		{"uses kill timeout", &structs.Task{KillTimeout: 20 * time.Second}, 20 * time.Second},
+		{"prefers shutdown delay over kill timeout", &structs.Task{ShutdownDelay: 10 * time.Second, KillTimeout: 20 * time.Second}, 10 * time.Second},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := hookTimeout(c.task); got != c.want {
+				t.Fatalf("expected %v, got %v", c.want, got)
+			}
+		})
+	}
+}
+
+type fakeContinueOnErrorHook struct {
+	continueOnError bool
+}
+
+func (h *fakeContinueOnErrorHook) Name() string { return "fake" }
+
+func (h *fakeContinueOnErrorHook) ContinueOnError() bool { return h.continueOnError }
+
+type fakePlainHook struct{}
+
+func (h *fakePlainHook) Name() string { return "plain" }
+
+func TestContinueOnHookError(t *testing.T) {
+	var plain interfaces.TaskHook = &fakePlainHook{}
+	if !continueOnHookError(plain) {
+		t.Fatalf("expected hooks without ContinueOnError to default to continuing")
+	}
+
+	var stopper interfaces.TaskHook = &fakeContinueOnErrorHook{continueOnError: false}
+	if continueOnHookError(stopper) {
+		t.Fatalf("expected hook's ContinueOnError() to be consulted")
+	}
+
+	var continuer interfaces.TaskHook = &fakeContinueOnErrorHook{continueOnError: true}
+	if !continueOnHookError(continuer) {
+		t.Fatalf("expected hook's ContinueOnError() to be consulted")
+	}
+}
+
+func TestUserCommandHook_ContinueOnError(t *testing.T) {
+	h := newUserCommandHook("stop", &structs.TaskHookCommand{Command: "/bin/false"}, &noopEmitter{}, hclog.NewNullLogger())
+	if h.ContinueOnError() {
+		t.Fatalf("expected userCommandHook to stop the phase on failure")
+	}
+}
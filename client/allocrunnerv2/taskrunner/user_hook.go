@@ -0,0 +1,192 @@
+package taskrunner
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/allocrunnerv2/interfaces"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// userCommandHook runs an operator-declared external command at a single
+// lifecycle phase, as configured in the task's `hooks` stanza. It gives
+// operators a lightweight alternative to writing a full driver plugin when
+// all they need is to shell out on prestart/poststart/stop/kill/update.
+type userCommandHook struct {
+	// phase is the lifecycle phase this instance was built for (eg
+	// "prestart"), since a task may declare a different command per phase.
+	phase string
+
+	cfg    *structs.TaskHookCommand
+	events interfaces.EventEmitter
+	logger hclog.Logger
+}
+
+func newUserCommandHook(phase string, cfg *structs.TaskHookCommand, events interfaces.EventEmitter, logger hclog.Logger) *userCommandHook {
+	return &userCommandHook{
+		phase:  phase,
+		cfg:    cfg,
+		events: events,
+		logger: logger.Named("user_hook"),
+	}
+}
+
+func (h *userCommandHook) Name() string {
+	return fmt.Sprintf("user-%s", h.phase)
+}
+
+// render interpolates s against vars, passing it through unchanged if vars
+// is nil.
+func (h *userCommandHook) render(s string, vars *interfaces.HookVars) (string, error) {
+	if vars == nil {
+		return s, nil
+	}
+	return vars.Render(s)
+}
+
+// execute runs the configured command once, capturing its output into the
+// task events stream, and returns the raw error from the command (if any)
+// with no on_error policy applied. Command, args, and env are rendered
+// against vars first so operators can reference alloc/env/prior-hook-output
+// fields, eg "{{.Env.NOMAD_ALLOC_ID}}".
+func (h *userCommandHook) execute(ctx context.Context, vars *interfaces.HookVars) error {
+	command, err := h.render(h.cfg.Command, vars)
+	if err != nil {
+		return err
+	}
+
+	args := make([]string, len(h.cfg.Args))
+	for i, a := range h.cfg.Args {
+		if args[i], err = h.render(a, vars); err != nil {
+			return err
+		}
+	}
+
+	// Default to the task's allocation directory, as documented on
+	// TaskHookCommand.WorkingDir, rather than the Nomad client agent's own
+	// working directory.
+	workingDir := h.cfg.WorkingDir
+	if workingDir == "" && vars != nil {
+		workingDir = vars.TaskDir
+	}
+
+	runCtx := ctx
+	if h.cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, h.cfg.Timeout)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(runCtx, command, args...)
+	cmd.Dir = workingDir
+
+	// Env is additive: start from the client process's environment, add the
+	// task's own environment (when vars is set), and layer the hook's
+	// declared env on top.
+	cmd.Env = os.Environ()
+	if vars != nil {
+		for k, v := range vars.Env {
+			cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", k, v))
+		}
+	}
+	for _, e := range h.cfg.Env {
+		rendered, err := h.render(e, vars)
+		if err != nil {
+			return err
+		}
+		cmd.Env = append(cmd.Env, rendered)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	runErr := cmd.Run()
+
+	h.events.EmitEvent(structs.NewTaskEvent(structs.TaskHookMessage).SetDisplayMessage(
+		fmt.Sprintf("%s hook %q: stdout=%q stderr=%q", h.phase, command, stdout.String(), stderr.String())))
+
+	// Record our own output so a later hook in the same invocation can
+	// reference it, eg "{{.Outputs.prestart}}".
+	if vars != nil {
+		vars.Outputs[h.phase] = stdout.String()
+	}
+
+	return runErr
+}
+
+// handle runs the command and applies the hook's on_error policy to the
+// result. done, when non-nil, is set to true unless the command failed
+// under the retry policy - the one case where a phase is gated on having
+// previously finished (see Prestart) and so needs to run the hook again on
+// its next invocation rather than treating this one as complete.
+func (h *userCommandHook) handle(ctx context.Context, vars *interfaces.HookVars, done *bool) error {
+	runErr := h.execute(ctx, vars)
+
+	if runErr == nil {
+		if done != nil {
+			*done = true
+		}
+		return nil
+	}
+
+	switch h.cfg.OnError {
+	case structs.TaskHookOnErrorIgnore:
+		h.logger.Warn("command hook failed, ignoring per on_error policy", "phase", h.phase, "error", runErr)
+		if done != nil {
+			*done = true
+		}
+		return nil
+	case structs.TaskHookOnErrorRetry:
+		h.logger.Warn("command hook failed, will retry on next invocation", "phase", h.phase, "error", runErr)
+		// Leave done unset so a phase gated on it (prestart) runs this
+		// hook again next time, instead of treating it as finished.
+		return nil
+	default:
+		if done != nil {
+			*done = true
+		}
+		return fmt.Errorf("command hook %q failed: %v", h.cfg.Command, runErr)
+	}
+}
+
+func (h *userCommandHook) Prestart(ctx context.Context, req *interfaces.TaskPrestartRequest, resp *interfaces.TaskPrestartResponse) error {
+	err := h.handle(ctx, req.Vars, &resp.Done)
+
+	// TaskRunner only persists resp.Done (as PrestartDone) when HookData is
+	// non-nil, so it has somewhere to store the hook's state alongside it.
+	// This hook keeps no state of its own, but still needs resp.Done
+	// persisted, so it must set a (possibly empty) HookData every time.
+	resp.HookData = map[string]string{}
+
+	return err
+}
+
+func (h *userCommandHook) Poststart(ctx context.Context, req *interfaces.TaskPoststartRequest, resp *interfaces.TaskPoststartResponse) error {
+	return h.handle(ctx, req.Vars, nil)
+}
+
+func (h *userCommandHook) Stop(ctx context.Context, req *interfaces.TaskStopRequest, resp *interfaces.TaskStopResponse) error {
+	return h.handle(ctx, req.Vars, nil)
+}
+
+func (h *userCommandHook) Kill(ctx context.Context, req *interfaces.TaskKillRequest, resp *interfaces.TaskKillResponse) error {
+	return h.handle(ctx, req.Vars, nil)
+}
+
+func (h *userCommandHook) Update(ctx context.Context, req *interfaces.TaskUpdateRequest, resp *interfaces.TaskUpdateResponse) error {
+	return h.handle(ctx, req.Vars, nil)
+}
+
+// ContinueOnError reports whether the lifecycle loop should keep running the
+// remaining hooks after this one fails. handle only ever returns an error
+// for the "fail" on_error policy - "ignore" and "retry" both absorb the
+// failure and return nil - so by the time this is consulted the hook has
+// asked to fail the phase outright, and the remaining hooks shouldn't run.
+func (h *userCommandHook) ContinueOnError() bool {
+	return false
+}
@@ -0,0 +1,105 @@
+package taskrunner
+
+import (
+	"context"
+	"testing"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/allocrunnerv2/interfaces"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+type noopEmitter struct {
+	events []*structs.TaskEvent
+}
+
+func (e *noopEmitter) EmitEvent(event *structs.TaskEvent) {
+	e.events = append(e.events, event)
+}
+
+func testHookVars() *interfaces.HookVars {
+	return &interfaces.HookVars{
+		TaskDir: "/tmp",
+		Env:     map[string]string{"NOMAD_TEST": "1"},
+		Outputs: make(map[string]string),
+	}
+}
+
+func TestUserCommandHook_Render(t *testing.T) {
+	h := newUserCommandHook("prestart", &structs.TaskHookCommand{Command: "/bin/true"}, &noopEmitter{}, hclog.NewNullLogger())
+
+	out, err := h.render("{{.Env.NOMAD_TEST}}", testHookVars())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out != "1" {
+		t.Fatalf("expected rendered value %q, got %q", "1", out)
+	}
+}
+
+func TestUserCommandHook_Execute_PopulatesOutputs(t *testing.T) {
+	cfg := &structs.TaskHookCommand{Command: "/bin/echo", Args: []string{"hello"}}
+	h := newUserCommandHook("prestart", cfg, &noopEmitter{}, hclog.NewNullLogger())
+
+	vars := testHookVars()
+	if err := h.execute(context.Background(), vars); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if vars.Outputs["prestart"] == "" {
+		t.Fatalf("expected prestart output to be recorded, got %q", vars.Outputs["prestart"])
+	}
+}
+
+func TestUserCommandHook_Handle_OnErrorIgnore(t *testing.T) {
+	cfg := &structs.TaskHookCommand{Command: "/bin/false", OnError: structs.TaskHookOnErrorIgnore}
+	h := newUserCommandHook("poststart", cfg, &noopEmitter{}, hclog.NewNullLogger())
+
+	var done bool
+	if err := h.handle(context.Background(), testHookVars(), &done); err != nil {
+		t.Fatalf("expected ignore policy to suppress error, got %v", err)
+	}
+	if !done {
+		t.Fatalf("expected done to be set under ignore policy")
+	}
+}
+
+func TestUserCommandHook_Handle_OnErrorRetry(t *testing.T) {
+	cfg := &structs.TaskHookCommand{Command: "/bin/false", OnError: structs.TaskHookOnErrorRetry}
+	h := newUserCommandHook("prestart", cfg, &noopEmitter{}, hclog.NewNullLogger())
+
+	done := false
+	if err := h.handle(context.Background(), testHookVars(), &done); err != nil {
+		t.Fatalf("expected retry policy to suppress error, got %v", err)
+	}
+	if done {
+		t.Fatalf("expected handle to leave done unset under retry policy so the hook reruns next invocation")
+	}
+}
+
+func TestUserCommandHook_Handle_OnErrorFail(t *testing.T) {
+	cfg := &structs.TaskHookCommand{Command: "/bin/false"}
+	h := newUserCommandHook("poststart", cfg, &noopEmitter{}, hclog.NewNullLogger())
+
+	if err := h.handle(context.Background(), testHookVars(), nil); err == nil {
+		t.Fatalf("expected default on_error policy to fail")
+	}
+}
+
+func TestUserCommandHook_Prestart_PersistsHookData(t *testing.T) {
+	cfg := &structs.TaskHookCommand{Command: "/bin/true"}
+	h := newUserCommandHook("prestart", cfg, &noopEmitter{}, hclog.NewNullLogger())
+
+	req := &interfaces.TaskPrestartRequest{Vars: testHookVars()}
+	var resp interfaces.TaskPrestartResponse
+	if err := h.Prestart(context.Background(), req, &resp); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if resp.HookData == nil {
+		t.Fatalf("expected HookData to be set so PrestartDone is persisted")
+	}
+	if !resp.Done {
+		t.Fatalf("expected Done to be true after a successful run")
+	}
+}
@@ -0,0 +1,52 @@
+package jobspec
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/hashicorp/nomad/api"
+	"github.com/mitchellh/mapstructure"
+)
+
+// parseTask parses a single "task" block, identified by item, into an
+// api.Task.
+func parseTask(item *ast.ObjectItem) (*api.Task, error) {
+	if len(item.Keys) == 0 {
+		return nil, fmt.Errorf("task: missing name")
+	}
+
+	taskObj, ok := item.Val.(*ast.ObjectType)
+	if !ok {
+		return nil, fmt.Errorf("task: should be an object")
+	}
+
+	var m map[string]interface{}
+	if err := hcl.DecodeObject(&m, taskObj.List); err != nil {
+		return nil, err
+	}
+
+	// hooks is parsed separately below since it nests further objects that
+	// DecodeObject/mapstructure can't decode directly into api.Task.
+	delete(m, "hooks")
+
+	var task api.Task
+	dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		WeaklyTypedInput: true,
+		Result:           &task,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := dec.Decode(m); err != nil {
+		return nil, err
+	}
+
+	task.Name = item.Keys[0].Token.Value().(string)
+
+	if err := parseTaskHooks(&task, &taskObj.List); err != nil {
+		return nil, fmt.Errorf("task %q: %v", task.Name, err)
+	}
+
+	return &task, nil
+}
@@ -0,0 +1,90 @@
+package jobspec
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/hashicorp/nomad/api"
+	"github.com/mitchellh/mapstructure"
+)
+
+// parseTaskHooks is called from parseTask for each task's ast.ObjectList,
+// looking for a "hooks" key and, if present, populating task.Hooks from it.
+func parseTaskHooks(task *api.Task, taskList *ast.ObjectList) error {
+	if o := taskList.Filter("hooks"); len(o.Items) > 0 {
+		if err := parseHooks(&task.Hooks, o); err != nil {
+			return fmt.Errorf("hooks: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// parseHooks parses the task's `hooks` stanza, which declares one external
+// command hook per lifecycle phase (prestart, poststart, stop, kill,
+// update), eg:
+//
+//	hooks {
+//	  prestart {
+//	    command = "/usr/bin/my-hook"
+//	    args    = ["--phase", "prestart"]
+//	    on_error = "fail"
+//	  }
+//	}
+func parseHooks(result *map[string]*api.TaskHookCommand, list *ast.ObjectList) error {
+	list = list.Elem()
+	if len(list.Items) == 0 {
+		return nil
+	}
+	if len(list.Items) > 1 {
+		return fmt.Errorf("only one 'hooks' block allowed per task")
+	}
+
+	obj := list.Items[0]
+	hooksList, ok := obj.Val.(*ast.ObjectType)
+	if !ok {
+		return fmt.Errorf("hooks: should be an object")
+	}
+
+	phases := hooksList.List.Items
+	if len(phases) == 0 {
+		return nil
+	}
+
+	hooks := make(map[string]*api.TaskHookCommand, len(phases))
+	for _, phase := range phases {
+		if len(phase.Keys) == 0 {
+			return fmt.Errorf("hooks: each phase must be named, eg 'prestart { ... }'")
+		}
+
+		name := phase.Keys[0].Token.Value().(string)
+		switch name {
+		case "prestart", "poststart", "stop", "kill", "update":
+		default:
+			return fmt.Errorf("hooks: unknown lifecycle phase %q", name)
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, phase.Val); err != nil {
+			return fmt.Errorf("hooks.%s: %v", name, err)
+		}
+
+		var cmd api.TaskHookCommand
+		dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+			WeaklyTypedInput: true,
+			Result:           &cmd,
+		})
+		if err != nil {
+			return err
+		}
+		if err := dec.Decode(m); err != nil {
+			return fmt.Errorf("hooks.%s: %v", name, err)
+		}
+
+		hooks[name] = &cmd
+	}
+
+	*result = hooks
+	return nil
+}
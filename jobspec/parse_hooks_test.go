@@ -0,0 +1,100 @@
+package jobspec
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/hashicorp/nomad/api"
+)
+
+// parseTestTask parses input as a single "task" block and returns the
+// resulting api.Task, failing the test on any error.
+func parseTestTask(t *testing.T, input string) *api.Task {
+	t.Helper()
+
+	root, err := hcl.Parse(input)
+	if err != nil {
+		t.Fatalf("failed to parse hcl: %v", err)
+	}
+
+	list, ok := root.Node.(*ast.ObjectList)
+	if !ok {
+		t.Fatalf("root should be an object list")
+	}
+
+	tasks := list.Filter("task")
+	if len(tasks.Items) != 1 {
+		t.Fatalf("expected exactly one task block, got %d", len(tasks.Items))
+	}
+
+	task, err := parseTask(tasks.Items[0])
+	if err != nil {
+		t.Fatalf("parseTask failed: %v", err)
+	}
+
+	return task
+}
+
+func TestParseTask_Hooks(t *testing.T) {
+	task := parseTestTask(t, `
+task "web" {
+	driver = "exec"
+
+	hooks {
+		prestart {
+			command  = "/usr/bin/my-hook"
+			args     = ["--phase", "prestart"]
+			on_error = "retry"
+		}
+	}
+}`)
+
+	if task.Name != "web" {
+		t.Fatalf("expected task name %q, got %q", "web", task.Name)
+	}
+
+	cmd := task.Hooks["prestart"]
+	if cmd == nil {
+		t.Fatalf("expected a prestart hook to be parsed")
+	}
+	if cmd.Command != "/usr/bin/my-hook" {
+		t.Fatalf("expected command %q, got %q", "/usr/bin/my-hook", cmd.Command)
+	}
+	if cmd.OnError != "retry" {
+		t.Fatalf("expected on_error %q, got %q", "retry", cmd.OnError)
+	}
+}
+
+func TestParseTask_NoHooks(t *testing.T) {
+	task := parseTestTask(t, `
+task "web" {
+	driver = "exec"
+}`)
+
+	if len(task.Hooks) != 0 {
+		t.Fatalf("expected no hooks, got %v", task.Hooks)
+	}
+}
+
+func TestParseHooks_UnknownPhase(t *testing.T) {
+	root, err := hcl.Parse(`
+task "web" {
+	driver = "exec"
+
+	hooks {
+		bogus {
+			command = "/usr/bin/my-hook"
+		}
+	}
+}`)
+	if err != nil {
+		t.Fatalf("failed to parse hcl: %v", err)
+	}
+
+	list := root.Node.(*ast.ObjectList)
+	task, err := parseTask(list.Filter("task").Items[0])
+	if err == nil {
+		t.Fatalf("expected error for unknown lifecycle phase, got task %+v", task)
+	}
+}
@@ -0,0 +1,113 @@
+package structs
+
+import (
+	"fmt"
+	"time"
+)
+
+// Vault stores the set of permissions a task needs access to from Vault.
+type Vault struct{}
+
+// Template represents a template and it's input.
+type Template struct{}
+
+// Task is a single process that will be run as part of a task group within
+// an allocation.
+type Task struct {
+	// Name of the task
+	Name string
+
+	// Driver is used to control which driver is used
+	Driver string
+
+	// Config is provided to the driver to initialize
+	Config map[string]interface{}
+
+	// Env is a map of environment variables to be used for the task
+	Env map[string]string
+
+	// Vault is used to configure Vault access for the task
+	Vault *Vault
+
+	// Templates are the set of templates to be rendered for the task
+	Templates []*Template
+
+	// Hooks declares operator-defined external command hooks, keyed by the
+	// lifecycle phase they run in (prestart, poststart, stop, kill, or
+	// update).
+	Hooks map[string]*TaskHookCommand
+
+	// ShutdownDelay is the duration to wait between deregistering
+	// the task from consul and stopping it.
+	ShutdownDelay time.Duration
+
+	// KillTimeout is the time between signaling a task kill and killing it
+	KillTimeout time.Duration
+
+	Meta map[string]string
+}
+
+// Copy returns a deep copy of the Task. It is expected that callers that
+// defensively copy a task that wont render a copy for inclusion in a task
+// group and use the NewTaskGroup() instead.
+func (t *Task) Copy() *Task {
+	if t == nil {
+		return nil
+	}
+
+	nt := new(Task)
+	*nt = *t
+
+	if t.Config != nil {
+		nt.Config = make(map[string]interface{}, len(t.Config))
+		for k, v := range t.Config {
+			nt.Config[k] = v
+		}
+	}
+
+	if t.Env != nil {
+		nt.Env = make(map[string]string, len(t.Env))
+		for k, v := range t.Env {
+			nt.Env[k] = v
+		}
+	}
+
+	if t.Meta != nil {
+		nt.Meta = make(map[string]string, len(t.Meta))
+		for k, v := range t.Meta {
+			nt.Meta[k] = v
+		}
+	}
+
+	if t.Hooks != nil {
+		nt.Hooks = make(map[string]*TaskHookCommand, len(t.Hooks))
+		for phase, cmd := range t.Hooks {
+			nt.Hooks[phase] = cmd.Copy()
+		}
+	}
+
+	return nt
+}
+
+// Validate is used to sanity check a task.
+func (t *Task) Validate() error {
+	if t.Name == "" {
+		return fmt.Errorf("missing task name")
+	}
+	if t.Driver == "" {
+		return fmt.Errorf("missing task driver")
+	}
+
+	for phase, cmd := range t.Hooks {
+		switch phase {
+		case "prestart", "poststart", "stop", "kill", "update":
+		default:
+			return fmt.Errorf("unknown hooks phase %q", phase)
+		}
+		if err := cmd.Validate(); err != nil {
+			return fmt.Errorf("hooks.%s: %v", phase, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package structs
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+)
+
+// ApiTaskToStructsTask converts an api.Task, the jobspec/API representation
+// parsed from HCL, into the structs.Task the TaskRunner actually executes.
+// It is responsible for parsing the string fields the API layer uses (eg
+// Timeout, OnError) into their structs equivalents.
+func ApiTaskToStructsTask(apiTask *api.Task) (*Task, error) {
+	task := &Task{
+		Name:   apiTask.Name,
+		Driver: apiTask.Driver,
+		Config: apiTask.Config,
+		Env:    apiTask.Env,
+		Meta:   apiTask.Meta,
+	}
+
+	if len(apiTask.Hooks) == 0 {
+		return task, nil
+	}
+
+	task.Hooks = make(map[string]*TaskHookCommand, len(apiTask.Hooks))
+	for phase, cmd := range apiTask.Hooks {
+		converted, err := apiTaskHookCommandToStructs(cmd)
+		if err != nil {
+			return nil, fmt.Errorf("hooks.%s: %v", phase, err)
+		}
+		task.Hooks[phase] = converted
+	}
+
+	return task, nil
+}
+
+// apiTaskHookCommandToStructs converts an api.TaskHookCommand into a
+// structs.TaskHookCommand, parsing Timeout into a time.Duration and
+// validating OnError against the known TaskHookOnErrorPolicy values.
+func apiTaskHookCommandToStructs(cmd *api.TaskHookCommand) (*TaskHookCommand, error) {
+	if cmd == nil {
+		return nil, nil
+	}
+
+	var timeout time.Duration
+	if cmd.Timeout != "" {
+		var err error
+		timeout, err = time.ParseDuration(cmd.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timeout %q: %v", cmd.Timeout, err)
+		}
+	}
+
+	onError := TaskHookOnErrorPolicy(cmd.OnError)
+	switch onError {
+	case "", TaskHookOnErrorFail, TaskHookOnErrorIgnore, TaskHookOnErrorRetry:
+	default:
+		return nil, fmt.Errorf("invalid on_error policy %q", cmd.OnError)
+	}
+
+	return &TaskHookCommand{
+		Command:    cmd.Command,
+		Args:       cmd.Args,
+		WorkingDir: cmd.WorkingDir,
+		Env:        cmd.Env,
+		Timeout:    timeout,
+		OnError:    onError,
+	}, nil
+}
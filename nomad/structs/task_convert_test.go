@@ -0,0 +1,65 @@
+package structs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+)
+
+func TestApiTaskToStructsTask(t *testing.T) {
+	apiTask := &api.Task{
+		Name:   "web",
+		Driver: "exec",
+		Hooks: map[string]*api.TaskHookCommand{
+			"prestart": {
+				Command: "/usr/bin/my-hook",
+				Args:    []string{"--phase", "prestart"},
+				Timeout: "5s",
+				OnError: "retry",
+			},
+		},
+	}
+
+	task, err := ApiTaskToStructsTask(apiTask)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cmd := task.Hooks["prestart"]
+	if cmd == nil {
+		t.Fatalf("expected prestart hook to be converted")
+	}
+	if cmd.Timeout != 5*time.Second {
+		t.Fatalf("expected timeout to be parsed to 5s, got %v", cmd.Timeout)
+	}
+	if cmd.OnError != TaskHookOnErrorRetry {
+		t.Fatalf("expected on_error to be converted to retry, got %v", cmd.OnError)
+	}
+}
+
+func TestApiTaskToStructsTask_InvalidTimeout(t *testing.T) {
+	apiTask := &api.Task{
+		Name: "web",
+		Hooks: map[string]*api.TaskHookCommand{
+			"prestart": {Command: "/usr/bin/my-hook", Timeout: "not-a-duration"},
+		},
+	}
+
+	if _, err := ApiTaskToStructsTask(apiTask); err == nil {
+		t.Fatalf("expected error for invalid timeout")
+	}
+}
+
+func TestApiTaskToStructsTask_InvalidOnError(t *testing.T) {
+	apiTask := &api.Task{
+		Name: "web",
+		Hooks: map[string]*api.TaskHookCommand{
+			"prestart": {Command: "/usr/bin/my-hook", OnError: "explode"},
+		},
+	}
+
+	if _, err := ApiTaskToStructsTask(apiTask); err == nil {
+		t.Fatalf("expected error for invalid on_error policy")
+	}
+}
@@ -0,0 +1,95 @@
+package structs
+
+import (
+	"fmt"
+	"time"
+)
+
+// TaskHookMessage is the task event type emitted for output produced by a
+// user command lifecycle hook.
+const TaskHookMessage = "User Hook"
+
+// TaskHookOnErrorPolicy controls what a user command lifecycle hook does
+// when the command it runs exits non-zero.
+type TaskHookOnErrorPolicy string
+
+const (
+	// TaskHookOnErrorFail fails the lifecycle phase the hook ran in. This
+	// is the default.
+	TaskHookOnErrorFail TaskHookOnErrorPolicy = "fail"
+
+	// TaskHookOnErrorIgnore logs the failure but otherwise treats the hook
+	// as if it had succeeded.
+	TaskHookOnErrorIgnore TaskHookOnErrorPolicy = "ignore"
+
+	// TaskHookOnErrorRetry asks the lifecycle loop to run the command again
+	// on the phase's next invocation rather than failing outright.
+	TaskHookOnErrorRetry TaskHookOnErrorPolicy = "retry"
+)
+
+// TaskHookCommand is a single operator-declared external command hook,
+// configured in a task's `hooks` stanza and bound to one lifecycle phase
+// (prestart, poststart, stop, kill, or update) via Task.Hooks.
+type TaskHookCommand struct {
+	// Command is the path to the executable to run.
+	Command string
+
+	// Args are passed to Command.
+	Args []string
+
+	// WorkingDir is the directory Command is run from. Defaults to the
+	// task's allocation directory.
+	WorkingDir string
+
+	// Env are additional environment variables set for Command, on top of
+	// the task's own environment.
+	Env []string
+
+	// Timeout bounds how long Command may run before it is killed. Zero
+	// means no explicit timeout is applied by the hook itself.
+	Timeout time.Duration
+
+	// OnError controls what happens when Command exits non-zero.
+	OnError TaskHookOnErrorPolicy
+}
+
+// Copy returns a deep copy of the TaskHookCommand.
+func (c *TaskHookCommand) Copy() *TaskHookCommand {
+	if c == nil {
+		return nil
+	}
+
+	nc := new(TaskHookCommand)
+	*nc = *c
+
+	if c.Args != nil {
+		nc.Args = make([]string, len(c.Args))
+		copy(nc.Args, c.Args)
+	}
+
+	if c.Env != nil {
+		nc.Env = make([]string, len(c.Env))
+		copy(nc.Env, c.Env)
+	}
+
+	return nc
+}
+
+// Validate returns an error if the hook command is not well formed.
+func (c *TaskHookCommand) Validate() error {
+	if c == nil {
+		return nil
+	}
+
+	if c.Command == "" {
+		return fmt.Errorf("hook command requires a command")
+	}
+
+	switch c.OnError {
+	case "", TaskHookOnErrorFail, TaskHookOnErrorIgnore, TaskHookOnErrorRetry:
+	default:
+		return fmt.Errorf("invalid on_error policy %q", c.OnError)
+	}
+
+	return nil
+}
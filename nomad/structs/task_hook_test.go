@@ -0,0 +1,68 @@
+package structs
+
+import "testing"
+
+func TestTaskHookCommand_Validate(t *testing.T) {
+	cases := []struct {
+		name    string
+		cmd     *TaskHookCommand
+		wantErr bool
+	}{
+		{
+			name:    "nil is valid",
+			cmd:     nil,
+			wantErr: false,
+		},
+		{
+			name:    "missing command",
+			cmd:     &TaskHookCommand{},
+			wantErr: true,
+		},
+		{
+			name:    "default on_error",
+			cmd:     &TaskHookCommand{Command: "/bin/true"},
+			wantErr: false,
+		},
+		{
+			name:    "known on_error",
+			cmd:     &TaskHookCommand{Command: "/bin/true", OnError: TaskHookOnErrorIgnore},
+			wantErr: false,
+		},
+		{
+			name:    "unknown on_error",
+			cmd:     &TaskHookCommand{Command: "/bin/true", OnError: "explode"},
+			wantErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.cmd.Validate()
+			if c.wantErr && err == nil {
+				t.Fatalf("expected error, got nil")
+			}
+			if !c.wantErr && err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestTaskHookCommand_Copy(t *testing.T) {
+	cmd := &TaskHookCommand{
+		Command: "/bin/true",
+		Args:    []string{"-c", "foo"},
+		Env:     []string{"FOO=bar"},
+	}
+
+	cp := cmd.Copy()
+	cp.Args[0] = "-x"
+	cp.Env[0] = "FOO=baz"
+
+	if cmd.Args[0] != "-c" {
+		t.Fatalf("expected original Args untouched, got %v", cmd.Args)
+	}
+	if cmd.Env[0] != "FOO=bar" {
+		t.Fatalf("expected original Env untouched, got %v", cmd.Env)
+	}
+}
@@ -0,0 +1,45 @@
+package structs
+
+import "testing"
+
+func TestTask_Validate_Hooks(t *testing.T) {
+	task := &Task{
+		Name:   "web",
+		Driver: "exec",
+		Hooks: map[string]*TaskHookCommand{
+			"prestart": {Command: "/usr/bin/my-hook"},
+		},
+	}
+
+	if err := task.Validate(); err != nil {
+		t.Fatalf("expected valid task, got %v", err)
+	}
+
+	task.Hooks["bogus-phase"] = &TaskHookCommand{Command: "/usr/bin/my-hook"}
+	if err := task.Validate(); err == nil {
+		t.Fatalf("expected error for unknown hooks phase")
+	}
+	delete(task.Hooks, "bogus-phase")
+
+	task.Hooks["poststart"] = &TaskHookCommand{}
+	if err := task.Validate(); err == nil {
+		t.Fatalf("expected error for invalid hook command")
+	}
+}
+
+func TestTask_Copy_Hooks(t *testing.T) {
+	task := &Task{
+		Name:   "web",
+		Driver: "exec",
+		Hooks: map[string]*TaskHookCommand{
+			"prestart": {Command: "/usr/bin/my-hook"},
+		},
+	}
+
+	cp := task.Copy()
+	cp.Hooks["prestart"].Command = "/usr/bin/other"
+
+	if task.Hooks["prestart"].Command != "/usr/bin/my-hook" {
+		t.Fatalf("expected original task's hook untouched, got %v", task.Hooks["prestart"].Command)
+	}
+}